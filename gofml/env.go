@@ -0,0 +1,292 @@
+package gofml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// envCommand is a grouping node for the commands that work with a gofml
+// project's synthetic GOPATH without needing direnv.
+var envCommand = Command{
+	Name:  "env",
+	Short: "work with a gofml project's synthetic GOPATH",
+}
+
+var envActivateCommand = Command{
+	Name:    "activate",
+	Short:   "print the shell exports to activate a gofml project",
+	Usage:   "env activate [-g][-n] <import path>",
+	Long:    "\nPrints \"export KEY=VALUE\" lines for GOFML/GOPATH/GOBIN/PATH (as the project's layout uses them), for use as `eval \"$(gofml env activate <import path>)\"`.\n",
+	GetTask: NewEnvActivateTask,
+}
+
+var envExecCommand = Command{
+	Name:    "exec",
+	Short:   "run a command inside a gofml project's GOPATH",
+	Usage:   "env exec [-g][-n] <import path> -- <command> [args...]",
+	GetTask: NewEnvExecTask,
+}
+
+var envDoctorCommand = Command{
+	Name:    "doctor",
+	Short:   "check that git and go are set up for gofml",
+	Usage:   "env doctor",
+	GetTask: NewEnvDoctorTask,
+}
+
+func init() {
+	envCommand.AddCommand(&envActivateCommand)
+	envCommand.AddCommand(&envExecCommand)
+	envCommand.AddCommand(&envDoctorCommand)
+
+	Root.AddCommand(&envCommand)
+}
+
+// CmdError describes a failed exec.Cmd invocation, including its captured
+// stdout/stderr, so callers can report what happened without re-running it.
+type CmdError struct {
+	Command string
+	Args    []string
+	Stdout  string
+	Stderr  string
+	Err     error
+}
+
+func (e *CmdError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Command, strings.Join(e.Args, " "), e.Err)
+}
+
+func (e *CmdError) Unwrap() error {
+	return e.Err
+}
+
+// mergeEnv overlays overrides onto the current process environment,
+// replacing any existing value for a key overrides also sets, the way a
+// shell `export` would. This keeps HOME/USER/LANG/SSH_AUTH_SOCK/etc.
+// available to a command run through `env exec`, instead of the child only
+// seeing the handful of vars gofml itself sets.
+func mergeEnv(overrides []string) []string {
+	keys := make(map[string]bool, len(overrides))
+
+	for _, kv := range overrides {
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			keys[kv[:i]] = true
+		}
+	}
+
+	merged := make([]string, 0, len(overrides))
+
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i >= 0 && keys[kv[:i]] {
+			continue
+		}
+
+		merged = append(merged, kv)
+	}
+
+	return append(merged, overrides...)
+}
+
+// runCmd runs name/args in dir with environ as its environment, streaming
+// stdout/stderr to the user while also capturing them, and returns a
+// *CmdError (with the captured output and exit status) on failure.
+func runCmd(dir string, environ []string, name string, args ...string) error {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = environ
+	cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	if err := cmd.Run(); err != nil {
+		return &CmdError{
+			Command: name,
+			Args:    args,
+			Stdout:  stdout.String(),
+			Stderr:  stderr.String(),
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// projectFlags parses the -g/-n flags and the import path shared by the
+// `env` commands, returning the flag set's remaining args.
+func projectFlags(name string, args []string) (gofmlRoot, projectName, importPath string, rest []string, err error) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+
+	g := flags.String("g", getGofmlRoot(), "the gofml root")
+	n := flags.String("n", "", "the project name")
+
+	flags.Parse(args)
+	rest = flags.Args()
+
+	if len(rest) < 1 {
+		return "", "", "", nil, errors.New("no import path specified")
+	}
+
+	return *g, *n, rest[0], rest[1:], nil
+}
+
+// resolveProjectEnv resolves a project's paths the way `env` commands need
+// to: GoFmlPath doesn't depend on Mod, but ProjectPath does, and the `env`
+// family (unlike `init`) doesn't take a -mod flag, so it reads back the Mod
+// the project was actually initialized with via the marker init wrote.
+func resolveProjectEnv(gofmlRoot, importPath, projectName string) (goFmlPath, projectPath string, mod ModMode) {
+	goFmlPath, _ = resolveProjectPaths(gofmlRoot, importPath, projectName, ModGopath)
+	mod = readModMarker(goFmlPath)
+	_, projectPath = resolveProjectPaths(gofmlRoot, importPath, projectName, mod)
+
+	return goFmlPath, projectPath, mod
+}
+
+// EnvActivateTask prints the shell exports needed to activate a project,
+// for use without direnv.
+type EnvActivateTask struct {
+	ImportPath  string
+	GoFmlPath   string
+	ProjectPath string
+	Mod         ModMode
+}
+
+// NewEnvActivateTask returns a new EnvActivateTask created with the
+// specified command-line args.
+func NewEnvActivateTask(args []string) (Task, error) {
+	gofmlRoot, projectName, importPath, _, err := projectFlags("env activate", args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	goFmlPath, projectPath, mod := resolveProjectEnv(gofmlRoot, importPath, projectName)
+
+	return &EnvActivateTask{ImportPath: importPath, GoFmlPath: goFmlPath, ProjectPath: projectPath, Mod: mod}, nil
+}
+
+// Run executes the EnvActivateTask.
+func (task *EnvActivateTask) Run() error {
+	for _, kv := range envVars(task.ImportPath, task.GoFmlPath, task.ProjectPath, task.Mod) {
+		fmt.Printf("export %s\n", kv)
+	}
+
+	return nil
+}
+
+// EnvExecTask runs an arbitrary command inside a project's synthetic
+// GOPATH, the way a direnv-activated shell would.
+type EnvExecTask struct {
+	ImportPath  string
+	GoFmlPath   string
+	ProjectPath string
+	Mod         ModMode
+	Command     string
+	Args        []string
+}
+
+// NewEnvExecTask returns a new EnvExecTask created with the specified
+// command-line args.
+func NewEnvExecTask(args []string) (Task, error) {
+	gofmlRoot, projectName, importPath, rest, err := projectFlags("env exec", args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) > 0 && rest[0] == "--" {
+		rest = rest[1:]
+	}
+
+	if len(rest) < 1 {
+		return nil, errors.New("no command specified")
+	}
+
+	goFmlPath, projectPath, mod := resolveProjectEnv(gofmlRoot, importPath, projectName)
+
+	return &EnvExecTask{
+		ImportPath:  importPath,
+		GoFmlPath:   goFmlPath,
+		ProjectPath: projectPath,
+		Mod:         mod,
+		Command:     rest[0],
+		Args:        rest[1:],
+	}, nil
+}
+
+// Run executes the EnvExecTask.
+func (task *EnvExecTask) Run() error {
+	environ := mergeEnv(envVars(task.ImportPath, task.GoFmlPath, task.ProjectPath, task.Mod))
+
+	return runCmd(task.ProjectPath, environ, task.Command, task.Args...)
+}
+
+// EnvDoctorTask checks that the tools gofml relies on are set up correctly.
+type EnvDoctorTask struct{}
+
+// NewEnvDoctorTask returns a new EnvDoctorTask created with the specified
+// command-line args.
+func NewEnvDoctorTask(args []string) (Task, error) {
+	return &EnvDoctorTask{}, nil
+}
+
+// Run executes the EnvDoctorTask.
+func (task *EnvDoctorTask) Run() error {
+	ok := true
+
+	if err := checkGitConfig("user.name"); err != nil {
+		ok = false
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if err := checkGitConfig("user.email"); err != nil {
+		ok = false
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if path, err := exec.LookPath("go"); err != nil {
+		ok = false
+		fmt.Fprintln(os.Stderr, "gofml: doctor: `go` not found on PATH")
+	} else {
+		fmt.Printf("gofml: doctor: go found at %s\n", path)
+	}
+
+	if !ok {
+		return errors.New("gofml: doctor found problems, see above")
+	}
+
+	fmt.Println("gofml: doctor: all checks passed")
+
+	return nil
+}
+
+// checkGitConfig verifies that git config has key set (in any scope),
+// offering to set it globally (like the gogs installer does) if it doesn't.
+func checkGitConfig(key string) error {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	val := strings.TrimSpace(string(out))
+
+	if err == nil && val != "" {
+		fmt.Printf("gofml: doctor: git config %s = %s\n", key, val)
+		return nil
+	}
+
+	fmt.Printf("gofml: doctor: git config %s is not set, enter a value to set it (or leave blank to skip): ", key)
+
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	if input == "" {
+		return fmt.Errorf("gofml: doctor: git config %s is not set", key)
+	}
+
+	return exec.Command("git", "config", "--global", key, input).Run()
+}
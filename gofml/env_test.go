@@ -0,0 +1,64 @@
+package gofml
+
+import "testing"
+
+func TestEnvVars(t *testing.T) {
+	cases := []struct {
+		mod  ModMode
+		want []string
+	}{
+		{ModGopath, []string{"GOFML=github.com/example/foo", "GOPATH=/root/foo", "PATH=/root/foo/bin:"}},
+		{ModModules, []string{"GOFML=github.com/example/foo", "GOBIN=/root/foo/foo/bin", "PATH=/root/foo/foo/bin:"}},
+		{ModHybrid, []string{"GOFML=github.com/example/foo", "GOPATH=/root/foo", "GOBIN=/root/foo/foo/bin", "PATH=/root/foo/foo/bin:/root/foo/bin:"}},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.mod), func(t *testing.T) {
+			t.Setenv("PATH", "")
+
+			got := envVars("github.com/example/foo", "/root/foo", "/root/foo/foo", c.mod)
+
+			if len(got) != len(c.want) {
+				t.Fatalf("envVars(%s) = %v, want %v", c.mod, got, c.want)
+			}
+
+			for i, kv := range got {
+				if kv != c.want[i] {
+					t.Errorf("envVars(%s)[%d] = %q, want %q", c.mod, i, kv, c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	t.Setenv("GOPATH", "/old/gopath")
+	t.Setenv("HOME", "/home/user")
+
+	merged := mergeEnv([]string{"GOPATH=/new/gopath"})
+
+	var gotGopath, gotHome string
+	gopathSeen := 0
+
+	for _, kv := range merged {
+		switch {
+		case len(kv) >= 7 && kv[:7] == "GOPATH=":
+			gotGopath = kv[7:]
+			gopathSeen++
+		case len(kv) >= 5 && kv[:5] == "HOME=":
+			gotHome = kv[5:]
+		}
+	}
+
+	if gopathSeen != 1 {
+		t.Fatalf("mergeEnv produced %d GOPATH entries, want 1", gopathSeen)
+	}
+
+	if gotGopath != "/new/gopath" {
+		t.Errorf("GOPATH = %q, want %q", gotGopath, "/new/gopath")
+	}
+
+	if gotHome != "/home/user" {
+		t.Errorf("HOME = %q, want %q (inherited environment should survive)", gotHome, "/home/user")
+	}
+}
@@ -0,0 +1,69 @@
+package gofml
+
+import (
+	"os"
+	"strings"
+	"text/template"
+)
+
+var usageTemplate = `
+{{.Cmd.Short}}
+
+Usage:
+
+    {{.Cmd.Path}} [command] [arguments]
+{{if .Cmd.Commands}}
+Commands:
+{{range .Cmd.Commands}}
+    {{.Name | printf "%-8s"}} {{.Short}}{{end}}
+
+Use "{{.RootPath}} help [command]" for more information about a command.
+{{end}}{{if .Cmd.Long}}
+{{.Cmd.Long}}{{end}}`
+
+var helpCommand = Command{
+	Name:  "help",
+	Short: "display help for a command",
+	Usage: "help [command]",
+	GetTask: func(args []string) (Task, error) {
+		return &helpTask{args: args}, nil
+	},
+}
+
+// helpTask renders usage for the command path given on the command line,
+// walking the subcommand tree the same way Command.Execute does.
+type helpTask struct {
+	args []string
+}
+
+func (t *helpTask) Run() error {
+	return helpCommand.show(t.args)
+}
+
+// show renders the usage of the command found by walking args from the
+// root, falling back to the closest match (e.g. "gofml help env bogus"
+// shows help for "env") the same way an unrecognized command does.
+func (c *Command) show(args []string) error {
+	cmd, _ := Root.Find(args)
+	cmd.usage()
+
+	return nil
+}
+
+func (c *Command) usage() {
+	tmpl := template.New("usage")
+	tmpl, err := tmpl.Parse(strings.TrimSpace(usageTemplate) + "\n\n")
+
+	if err != nil {
+		panic(err)
+	}
+
+	err = tmpl.Execute(os.Stderr, struct {
+		Cmd      *Command
+		RootPath string
+	}{c, Root.Path()})
+
+	if err != nil {
+		panic(err)
+	}
+}
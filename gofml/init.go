@@ -8,23 +8,48 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
+
+	"github.com/rubensayshi/gofml/gofml/paths"
+)
+
+// ModMode selects the Go project layout InitTask creates.
+type ModMode string
+
+const (
+	// ModGopath creates a GOPATH-style src/<import-path> tree (the default,
+	// and the only layout gofml originally supported).
+	ModGopath ModMode = "gopath"
+	// ModModules creates a flat project directory with a generated go.mod
+	// and no GOPATH at all.
+	ModModules ModMode = "modules"
+	// ModHybrid creates both: a flat project directory with a go.mod, and a
+	// GOPATH src/<import-path> symlink pointing at it.
+	ModHybrid ModMode = "hybrid"
 )
 
-const script = `
+const scriptGopath = `
 export GOFML={{.ImportPath}}
 export GOPATH={{.GoFmlPath}}
 export PATH="$GOPATH/bin:$PATH"
 `
 
-func getGofmlRoot() string {
-	root := os.Getenv("GOFMLROOT")
+const scriptModules = `
+export GOFML={{.ImportPath}}
+export GOBIN={{.ProjectPath}}/bin
+export PATH="$GOBIN:$PATH"
+`
 
-	if root == "" {
-		root = "~/gofml"
-	}
+const scriptHybrid = `
+export GOFML={{.ImportPath}}
+export GOPATH={{.GoFmlPath}}
+export GOBIN={{.ProjectPath}}/bin
+export PATH="$GOBIN:$GOPATH/bin:$PATH"
+`
 
-	return root
+func getGofmlRoot() string {
+	return paths.Root()
 }
 
 var GOFMLROOT string = getGofmlRoot()
@@ -32,7 +57,7 @@ var GOFMLROOT string = getGofmlRoot()
 var initCommand = Command{
 	Name:  "init",
 	Short: "initialize a gofml env",
-	Usage: "init [-g][-n] [import path]",
+	Usage: "init [-g][-n][-mod] [import path]",
 	Long: fmt.Sprintf(`
 Init supports the following options:
 
@@ -42,15 +67,29 @@ Init supports the following options:
     -n
          the name of the environment, defaulting to the basename of the import path.
 
+    -mod
+         the project layout: "gopath" (default), "modules", or "hybrid". "modules" skips the
+         GOPATH src/ tree and creates a flat project directory with a generated go.mod instead.
+         "hybrid" creates both.
+
+    -template
+         a project scaffold to generate in addition to the .envrc: "main", "lib", "cli", "grpc",
+         or a path/git URL to a custom template directory. Defaults to no scaffold.
+
+Note: init does not export a gomod2nix.toml-style dependency lock; it only
+generates go.mod via `+"`go mod init`"+`.
+
 `, GOFMLROOT),
 	GetTask: NewInitTask,
 }
 
 // InitTask initializes a gofml.
 type InitTask struct {
-	GoFmlRoot   string // the gofml root to create envs in, default "~/.gofml" or uses env $GOFMLROOT
-	ImportPath  string // the import path of the project, e.g. "github.com/rubensayshi/gofml"
-	ProjectName string // the name of the project, e.g. "gofml".
+	GoFmlRoot   string  // the gofml root to create envs in, defaults to paths.Root() ($GOFMLROOT if set, else the XDG data dir)
+	ImportPath  string  // the import path of the project, e.g. "github.com/rubensayshi/gofml"
+	ProjectName string  // the name of the project, e.g. "gofml".
+	Mod         ModMode // the project layout: gopath, modules, or hybrid
+	Template    string  // the scaffold to generate: "main", "lib", "cli", "grpc", or a path/git URL; empty for none
 
 	GoFmlPath   string // GoFmlRoot + ProjectName
 	ProjectPath string // the path to the project
@@ -59,10 +98,24 @@ type InitTask struct {
 // NewInitTask returns a new InitTask created with the specified command-line args.
 func NewInitTask(args []string) (Task, error) {
 
+	cfg, err := paths.LoadConfig()
+
+	if err != nil {
+		return nil, err
+	}
+
+	modDefault := string(ModGopath)
+
+	if cfg.Mod != "" {
+		modDefault = cfg.Mod
+	}
+
 	flags := flag.NewFlagSet("init", flag.ExitOnError)
 
 	gofmlRoot := flags.String("g", getGofmlRoot(), "the gofml root")
 	projectName := flags.String("n", "", "the project name")
+	mod := flags.String("mod", modDefault, "the project layout: gopath, modules, or hybrid")
+	tmpl := flags.String("template", cfg.Template, "a project scaffold: main, lib, cli, grpc, or a path/git URL")
 
 	flags.Parse(args)
 	args = flags.Args()
@@ -71,10 +124,26 @@ func NewInitTask(args []string) (Task, error) {
 		return nil, errors.New("no import path specified")
 	}
 
+	modMode := ModMode(*mod)
+
+	switch modMode {
+	case ModGopath, ModModules, ModHybrid:
+	default:
+		return nil, fmt.Errorf("invalid -mod %q, must be one of gopath, modules, hybrid", *mod)
+	}
+
+	importPath := args[0]
+
+	if cfg.ImportPathPrefix != "" && !strings.Contains(importPath, "/") {
+		importPath = strings.TrimSuffix(cfg.ImportPathPrefix, "/") + "/" + importPath
+	}
+
 	task := InitTask{
-		ImportPath:  args[0],
+		ImportPath:  importPath,
 		GoFmlRoot:   *gofmlRoot,
 		ProjectName: *projectName,
+		Mod:         modMode,
+		Template:    *tmpl,
 		ProjectPath: "",
 		GoFmlPath:   "",
 	}
@@ -83,15 +152,55 @@ func NewInitTask(args []string) (Task, error) {
 		task.ProjectName = filepath.Base(task.ImportPath)
 	}
 
-	if task.GoFmlPath == "" {
-		task.GoFmlPath = filepath.Join(task.GoFmlRoot, task.ProjectName)
+	task.GoFmlPath, task.ProjectPath = resolveProjectPaths(task.GoFmlRoot, task.ImportPath, task.ProjectName, task.Mod)
+
+	return &task, nil
+}
+
+// resolveProjectPaths applies gofml's project layout rules: the project's
+// own directory (GoFmlPath) lives under the gofml root. In ModGopath the
+// project sources (ProjectPath) live under a GOPATH-style src/ tree; in
+// ModModules and ModHybrid they live in a flat GoFmlPath/<project name>
+// directory (which ModHybrid then symlinks into src/). It's shared
+// by `init` and the `env` command family so both agree on where a project's
+// synthetic GOPATH lives.
+func resolveProjectPaths(gofmlRoot, importPath, projectName string, mod ModMode) (goFmlPath, projectPath string) {
+	if projectName == "" {
+		projectName = filepath.Base(importPath)
 	}
 
-	if task.ProjectPath == "" {
-		task.ProjectPath = filepath.Join(task.GoFmlPath, "src", task.ImportPath)
+	goFmlPath = filepath.Join(gofmlRoot, projectName)
+
+	if mod == ModModules || mod == ModHybrid {
+		projectPath = filepath.Join(goFmlPath, projectName)
+	} else {
+		projectPath = filepath.Join(goFmlPath, "src", importPath)
 	}
 
-	return &task, nil
+	return goFmlPath, projectPath
+}
+
+// modMarkerFile records the Mod a project was initialized with, so that
+// commands which only take an import path (e.g. `env exec`) can look up how
+// the project is laid out without the caller having to repeat -mod.
+const modMarkerFile = ".gofml-mod"
+
+// writeModMarker persists task.Mod under task.GoFmlPath.
+func (task *InitTask) writeModMarker() error {
+	return ioutil.WriteFile(filepath.Join(task.GoFmlPath, modMarkerFile), []byte(string(task.Mod)+"\n"), 0664)
+}
+
+// readModMarker reads back the Mod a project at goFmlPath was initialized
+// with, defaulting to ModGopath for projects created before the marker
+// existed.
+func readModMarker(goFmlPath string) ModMode {
+	data, err := ioutil.ReadFile(filepath.Join(goFmlPath, modMarkerFile))
+
+	if err != nil {
+		return ModGopath
+	}
+
+	return ModMode(strings.TrimSpace(string(data)))
 }
 
 // Run exeuctes the InitTask
@@ -109,6 +218,18 @@ func (task *InitTask) Run() error {
 		return err
 	}
 
+	if err := task.writeModMarker(); err != nil {
+		return err
+	}
+
+	if err := task.writeGoMod(); err != nil {
+		return err
+	}
+
+	if err := task.writeScaffold(); err != nil {
+		return err
+	}
+
 	if err := task.writeEnvrc(); err != nil {
 		return err
 	}
@@ -124,17 +245,98 @@ func (task *InitTask) Run() error {
 
 // writeScript writes the gofml activate script.
 func (task *InitTask) makeDir() error {
-	err := os.MkdirAll(task.ProjectPath, os.ModeDir|0775)
-
 	fmt.Printf("gofml: create project directory %s\n", task.ProjectPath)
 
-	return err
+	if err := os.MkdirAll(task.ProjectPath, os.ModeDir|0775); err != nil {
+		return err
+	}
+
+	if task.Mod != ModHybrid {
+		return nil
+	}
+
+	gopathDir := filepath.Join(task.GoFmlPath, "src", task.ImportPath)
+
+	if err := os.MkdirAll(filepath.Dir(gopathDir), os.ModeDir|0775); err != nil {
+		return err
+	}
+
+	_ = os.Remove(gopathDir)
+
+	fmt.Printf("gofml: symlink GOPATH src %s -> %s\n", gopathDir, task.ProjectPath)
+
+	return os.Symlink(task.ProjectPath, gopathDir)
+}
+
+// writeGoMod generates a go.mod for the project in modules/hybrid mode by
+// shelling out to `go mod init`, leaving any existing go.mod untouched.
+//
+// This deliberately does not export a gomod2nix.toml-style lock file;
+// that's out of scope for now and left for a future `-template`/scaffold
+// to add if it's needed.
+func (task *InitTask) writeGoMod() error {
+	if task.Mod == ModGopath {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(task.ProjectPath, "go.mod")); err == nil {
+		return nil
+	}
+
+	fmt.Printf("gofml: running `go mod init %s` in %s\n", task.ImportPath, task.ProjectPath)
+
+	return runCmd(task.ProjectPath, os.Environ(), "go", "mod", "init", task.ImportPath)
+}
+
+// writeScaffold generates the project's starter file tree, if -template was
+// given.
+func (task *InitTask) writeScaffold() error {
+	if task.Template == "" {
+		return nil
+	}
+
+	scaffold, err := resolveScaffold(task.Template)
+
+	if err != nil {
+		return err
+	}
+
+	files, err := scaffold.Files(task)
+
+	if err != nil {
+		return err
+	}
+
+	for name, contents := range files {
+		path := filepath.Join(task.ProjectPath, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModeDir|0775); err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(path, contents, 0664); err != nil {
+			return err
+		}
+
+		fmt.Printf("gofml: wrote %s\n", path)
+	}
+
+	return nil
 }
 
 // writeScript writes the gofml activate script.
 func (task *InitTask) writeEnvrc() error {
 
-	scriptTemplate := template.New("test")
+	script := scriptGopath
+
+	switch task.Mod {
+	case ModModules:
+		script = scriptModules
+	case ModHybrid:
+		script = scriptHybrid
+	}
+
+	scriptTemplate := template.New("envrc")
 	scriptTemplate, err := scriptTemplate.Parse(script)
 
 	if err != nil {
@@ -159,7 +361,40 @@ func (task *InitTask) writeEnvrc() error {
 // writeScript writes the gofml activate script.
 func (task *InitTask) printHints() error {
 
-	fmt.Printf("gofml: now do `cd %s` to goto your project and then `direnv allow` to activate the direnv file. \n", task.ProjectPath)
+	fmt.Printf("gofml: now do `cd %s` to goto your project and then `direnv allow` to activate the direnv file, "+
+		"or run `gofml env exec %s -- <command>` if you don't use direnv. \n", task.ProjectPath, task.ImportPath)
 
 	return nil
 }
+
+// envVars returns the GOFML/GOPATH/GOBIN/PATH environment variables gofml
+// sets up for a project, in "KEY=VALUE" form. It mirrors whichever of
+// scriptGopath/scriptModules/scriptHybrid writeEnvrc picked for mod, so a
+// command run through `gofml env exec`/`env activate` sees the same
+// environment a direnv-activated shell would.
+func envVars(importPath, goFmlPath, projectPath string, mod ModMode) []string {
+	path := os.Getenv("PATH")
+	gobin := filepath.Join(projectPath, "bin")
+
+	switch mod {
+	case ModModules:
+		return []string{
+			"GOFML=" + importPath,
+			"GOBIN=" + gobin,
+			"PATH=" + gobin + ":" + path,
+		}
+	case ModHybrid:
+		return []string{
+			"GOFML=" + importPath,
+			"GOPATH=" + goFmlPath,
+			"GOBIN=" + gobin,
+			"PATH=" + gobin + ":" + filepath.Join(goFmlPath, "bin") + ":" + path,
+		}
+	default:
+		return []string{
+			"GOFML=" + importPath,
+			"GOPATH=" + goFmlPath,
+			"PATH=" + filepath.Join(goFmlPath, "bin") + ":" + path,
+		}
+	}
+}
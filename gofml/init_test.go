@@ -0,0 +1,41 @@
+package gofml
+
+import "testing"
+
+func TestResolveProjectPaths(t *testing.T) {
+	cases := []struct {
+		mod             ModMode
+		wantGoFmlPath   string
+		wantProjectPath string
+	}{
+		{ModGopath, "/root/foo", "/root/foo/src/github.com/example/foo"},
+		{ModModules, "/root/foo", "/root/foo/foo"},
+		{ModHybrid, "/root/foo", "/root/foo/foo"},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.mod), func(t *testing.T) {
+			goFmlPath, projectPath := resolveProjectPaths("/root", "github.com/example/foo", "foo", c.mod)
+
+			if goFmlPath != c.wantGoFmlPath {
+				t.Errorf("goFmlPath = %q, want %q", goFmlPath, c.wantGoFmlPath)
+			}
+
+			if projectPath != c.wantProjectPath {
+				t.Errorf("projectPath = %q, want %q", projectPath, c.wantProjectPath)
+			}
+		})
+	}
+}
+
+func TestResolveProjectPathsDefaultsProjectName(t *testing.T) {
+	goFmlPath, projectPath := resolveProjectPaths("/root", "github.com/example/foo", "", ModGopath)
+
+	if want := "/root/foo"; goFmlPath != want {
+		t.Errorf("goFmlPath = %q, want %q", goFmlPath, want)
+	}
+
+	if want := "/root/foo/src/github.com/example/foo"; projectPath != want {
+		t.Errorf("projectPath = %q, want %q", projectPath, want)
+	}
+}
@@ -1,105 +1,205 @@
 package gofml
 
 import (
-	"flag"
 	"fmt"
 	"os"
 	"strings"
-	"text/template"
 )
 
-var usageTemplate = `
-GoFML helps organize go projects.
-
-Usage:
-
-    gofml [command] [arguments]
-
-Commands:
-{{ range . }}
-    {{ .Name | printf "%-8s" }} {{ .Short }}{{end}}
-
-Use "gofml help [command]" for command-specific information.
-`
+// Task is an action.
+type Task interface {
+	Run() error
+}
 
-// Command is a command-line action.
+// Command is a command-line action, optionally with nested subcommands. A
+// Command with no GetTask is a pure grouping node (e.g. "env") that exists
+// only to hold subcommands.
 type Command struct {
 	Name    string
 	Usage   string
 	Short   string
 	Long    string
 	GetTask func([]string) (Task, error)
-}
 
-// Task is an action.
-type Task interface {
-	Run() error
+	Commands []*Command
+
+	parent *Command
 }
 
-// a map of command names -> commands.
-var commands map[string]*Command
-var usageText string
+// Root is the top-level gofml command; every other command is registered as
+// a descendant of it.
+var Root = &Command{
+	Name:  "gofml",
+	Short: "GoFML helps organize go projects.",
+}
 
 func init() {
+	Root.AddCommand(&initCommand)
+	Root.AddCommand(&helpCommand)
+}
 
-	commands = make(map[string]*Command)
-	commandList := []*Command{
-		&initCommand,
-		&helpCommand,
-	}
+// AddCommand registers cmd as a subcommand of c.
+func (c *Command) AddCommand(cmd *Command) {
+	cmd.parent = c
+	c.Commands = append(c.Commands, cmd)
+}
 
-	for _, cmd := range commandList {
-		commands[cmd.Name] = cmd
-	}
+// Runnable reports whether c can be executed directly, as opposed to being
+// a grouping node that only holds subcommands.
+func (c *Command) Runnable() bool {
+	return c.GetTask != nil
 }
 
-func usage() {
+// Path returns the full command path, e.g. "gofml env activate".
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
 
-	tmpl := template.New("usage")
-	tmpl, err := tmpl.Parse(strings.TrimSpace(usageTemplate) + "\n\n")
+	return c.parent.Path() + " " + c.Name
+}
 
-	if err != nil {
-		panic(err)
+// Find walks args against c's subcommand tree, descending for as long as
+// args[0] names a subcommand, and returns the most specific command matched
+// along with the remaining, unconsumed args.
+func (c *Command) Find(args []string) (*Command, []string) {
+	if len(args) == 0 {
+		return c, args
 	}
 
-	err = tmpl.Execute(os.Stderr, commands)
-
-	if err != nil {
-		panic(err)
+	for _, sub := range c.Commands {
+		if sub.Name == args[0] {
+			return sub.Find(args[1:])
+		}
 	}
+
+	return c, args
 }
 
-func Main() {
+// suggest returns the names of c's subcommands that are likely typos of
+// name, for use in "did you mean" hints.
+func (c *Command) suggest(name string) []string {
+	var suggestions []string
 
-	flag.Usage = usage
-	flag.Parse()
+	threshold := len(name)/2 + 1
+
+	for _, sub := range c.Commands {
+		if levenshtein(sub.Name, name) <= threshold {
+			suggestions = append(suggestions, sub.Name)
+		}
+	}
 
-	args := flag.Args()
+	return suggestions
+}
+
+// Execute parses os.Args and runs the matched (sub)command. It is the
+// entrypoint for the gofml binary, replacing the old Main().
+func (c *Command) Execute() error {
+	return c.execute(os.Args[1:])
+}
 
-	if len(args) < 1 || len(args) == 1 && args[0] == "help" {
-		flag.Usage()
+func (c *Command) execute(args []string) error {
+	if len(args) > 0 && args[0] == "help" {
+		return helpCommand.show(args[1:])
+	}
+
+	if len(args) == 0 {
+		c.usage()
 		os.Exit(1)
 	}
 
-	cmd, found := commands[args[0]]
+	cmd, rest := c.Find(args)
+
+	if !cmd.Runnable() {
+		// cmd is the deepest node Find matched. If it consumed every arg,
+		// args names a real (grouping) command, e.g. "gofml env" — show its
+		// help rather than claiming it doesn't exist. Otherwise rest[0] is
+		// the token that failed to match any of cmd's subcommands.
+		if len(rest) == 0 {
+			cmd.usage()
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "gofml: unrecognized command %q\n", strings.Join(args, " "))
 
-	if !found {
-		fmt.Fprintf(os.Stderr, "gofml: unrecognized command %s\n", args[0])
-		flag.Usage()
+		if suggestions := cmd.suggest(rest[0]); len(suggestions) > 0 {
+			fmt.Fprintf(os.Stderr, "\nDid you mean this?\n")
+			for _, s := range suggestions {
+				fmt.Fprintf(os.Stderr, "\t%s\n", s)
+			}
+		}
+
+		cmd.usage()
 		os.Exit(1)
 	}
 
-	task, err := cmd.GetTask(args[1:])
+	task, err := cmd.GetTask(rest)
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "gofml: failed to parse command %s\n", cmd)
+		fmt.Fprintf(os.Stderr, "gofml: failed to parse command %s: %s\n", cmd.Path(), err)
 		os.Exit(1)
 	}
 
-	err = task.Run()
-
-	if err != nil {
+	if err := task.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "gofml: error running command: %s\n", err)
 		os.Exit(1)
 	}
+
+	return nil
+}
+
+// Main runs the gofml binary. It is kept as a thin wrapper around
+// Root.Execute for existing callers.
+func Main() {
+	_ = Root.Execute()
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	la, lb := len(a), len(b)
+
+	if la == 0 {
+		return lb
+	}
+
+	if lb == 0 {
+		return la
+	}
+
+	prev := make([]int, lb+1)
+	curr := make([]int, lb+1)
+
+	for j := 0; j <= lb; j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		curr[0] = i
+
+		for j := 1; j <= lb; j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+
+			min := del
+			if ins < min {
+				min = ins
+			}
+			if sub < min {
+				min = sub
+			}
+
+			curr[j] = min
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[lb]
 }
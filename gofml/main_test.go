@@ -0,0 +1,94 @@
+package gofml
+
+import (
+	"strings"
+	"testing"
+)
+
+// testTree builds an isolated "gofml env activate/exec/doctor" tree, so
+// Find/suggest can be exercised without touching the package-level Root.
+func testTree() *Command {
+	root := &Command{Name: "gofml"}
+	env := &Command{Name: "env"}
+
+	env.AddCommand(&Command{Name: "activate", GetTask: func([]string) (Task, error) { return nil, nil }})
+	env.AddCommand(&Command{Name: "exec", GetTask: func([]string) (Task, error) { return nil, nil }})
+	env.AddCommand(&Command{Name: "doctor", GetTask: func([]string) (Task, error) { return nil, nil }})
+
+	root.AddCommand(env)
+	root.AddCommand(&Command{Name: "init", GetTask: func([]string) (Task, error) { return nil, nil }})
+
+	return root
+}
+
+func TestCommandFind(t *testing.T) {
+	root := testTree()
+
+	cases := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantRest []string
+	}{
+		{"leaf with trailing args", []string{"env", "activate", "foo"}, "activate", []string{"foo"}},
+		{"exact grouping command", []string{"env"}, "env", []string{}},
+		{"unmatched token under a group", []string{"env", "bogus"}, "env", []string{"bogus"}},
+		{"unmatched top-level token", []string{"bogus"}, "gofml", []string{"bogus"}},
+		{"no args", []string{}, "gofml", []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cmd, rest := root.Find(c.args)
+
+			if cmd.Name != c.wantName {
+				t.Errorf("Find(%v) cmd = %q, want %q", c.args, cmd.Name, c.wantName)
+			}
+
+			if strings.Join(rest, ",") != strings.Join(c.wantRest, ",") {
+				t.Errorf("Find(%v) rest = %v, want %v", c.args, rest, c.wantRest)
+			}
+		})
+	}
+}
+
+func TestCommandSuggest(t *testing.T) {
+	root := testTree()
+	env, _ := root.Find([]string{"env"})
+
+	cases := []struct {
+		name   string
+		typo   string
+		wanted string
+	}{
+		{"single edit", "activat", "activate"},
+		{"transposition", "exce", "exec"},
+		{"nothing close", "zzzzzzzzzz", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			suggestions := env.suggest(c.typo)
+
+			if c.wanted == "" {
+				if len(suggestions) != 0 {
+					t.Errorf("suggest(%q) = %v, want none", c.typo, suggestions)
+				}
+
+				return
+			}
+
+			found := false
+
+			for _, s := range suggestions {
+				if s == c.wanted {
+					found = true
+				}
+			}
+
+			if !found {
+				t.Errorf("suggest(%q) = %v, want to include %q", c.typo, suggestions, c.wanted)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package paths
+
+import (
+	"os"
+	"strings"
+)
+
+// Config is gofml's user config, loaded from ConfigFile(). It supports a
+// flat subset of TOML: blank lines, "# comment" lines, and
+// `key = "value"` assignments.
+type Config struct {
+	// ImportPathPrefix is prepended to a bare project name (one with no
+	// "/") passed to `init`, so e.g. "myproj" becomes
+	// "github.com/me/myproj".
+	ImportPathPrefix string
+	// Template is the default `-template` value for `init`.
+	Template string
+	// Mod is the default `-mod` value for `init`.
+	Mod string
+}
+
+// LoadConfig reads the user config file, returning a zero Config if it
+// doesn't exist.
+func LoadConfig() (Config, error) {
+	data, err := os.ReadFile(ConfigFile())
+
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	return parseConfig(string(data)), nil
+}
+
+func parseConfig(data string) Config {
+	var cfg Config
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch key {
+		case "import_path_prefix":
+			cfg.ImportPathPrefix = value
+		case "template":
+			cfg.Template = value
+		case "mod":
+			cfg.Mod = value
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,36 @@
+package paths
+
+import "testing"
+
+func TestParseConfig(t *testing.T) {
+	data := `
+# a comment
+import_path_prefix = "github.com/rubensayshi"
+template = "cli"
+mod = "modules"
+
+unknown_key = "ignored"
+`
+
+	cfg := parseConfig(data)
+
+	if cfg.ImportPathPrefix != "github.com/rubensayshi" {
+		t.Errorf("ImportPathPrefix = %q, want %q", cfg.ImportPathPrefix, "github.com/rubensayshi")
+	}
+
+	if cfg.Template != "cli" {
+		t.Errorf("Template = %q, want %q", cfg.Template, "cli")
+	}
+
+	if cfg.Mod != "modules" {
+		t.Errorf("Mod = %q, want %q", cfg.Mod, "modules")
+	}
+}
+
+func TestParseConfigEmpty(t *testing.T) {
+	cfg := parseConfig("")
+
+	if cfg != (Config{}) {
+		t.Errorf("parseConfig(\"\") = %+v, want zero value", cfg)
+	}
+}
@@ -0,0 +1,79 @@
+// Package paths resolves gofml's on-disk locations (project root, user
+// config, and caches) following the XDG Base Directory spec, falling back
+// to $GOFMLROOT and finally ~/gofml for backward compatibility.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// expandHome expands a leading "~" to the current user's home directory.
+func expandHome(path string) string {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return path
+	}
+
+	if path == "~" {
+		return home
+	}
+
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+
+	return path
+}
+
+// Root resolves the root directory gofml creates project envs in:
+// $GOFMLROOT if set (for backward compatibility), else
+// $XDG_DATA_HOME/gofml, else ~/.local/share/gofml, and only ~/gofml if none
+// of those are resolvable.
+func Root() string {
+	if root := os.Getenv("GOFMLROOT"); root != "" {
+		return expandHome(root)
+	}
+
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "gofml")
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		return filepath.Join(home, ".local", "share", "gofml")
+	}
+
+	return expandHome("~/gofml")
+}
+
+// ConfigFile resolves gofml's user config file:
+// $XDG_CONFIG_HOME/gofml/config.toml, defaulting to ~/.config/gofml/config.toml.
+func ConfigFile() string {
+	return filepath.Join(configHome(), "gofml", "config.toml")
+}
+
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+
+	return expandHome("~/.config")
+}
+
+// CacheDir resolves gofml's cache root: $XDG_CACHE_HOME/gofml, defaulting
+// to ~/.cache/gofml.
+func CacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "gofml")
+	}
+
+	return expandHome("~/.cache/gofml")
+}
+
+// TemplateCacheDir resolves where `init -template` caches cloned git
+// template repos.
+func TemplateCacheDir() string {
+	return filepath.Join(CacheDir(), "templates")
+}
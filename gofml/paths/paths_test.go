@@ -0,0 +1,73 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		t.Skipf("no home dir available: %s", err)
+	}
+
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"~", home},
+		{"~/gofml", filepath.Join(home, "gofml")},
+		{"/abs/path", "/abs/path"},
+		{"relative/path", "relative/path"},
+	}
+
+	for _, c := range cases {
+		if got := expandHome(c.in); got != c.want {
+			t.Errorf("expandHome(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestRootPrefersGofmlrootThenXDG(t *testing.T) {
+	t.Setenv("GOFMLROOT", "/custom/root")
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+
+	if got, want := Root(), "/custom/root"; got != want {
+		t.Errorf("Root() = %q, want %q", got, want)
+	}
+
+	t.Setenv("GOFMLROOT", "")
+
+	if got, want := Root(), filepath.Join("/xdg/data", "gofml"); got != want {
+		t.Errorf("Root() = %q, want %q", got, want)
+	}
+}
+
+func TestRootFallsBackToLocalShareWithoutXDG(t *testing.T) {
+	t.Setenv("GOFMLROOT", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		t.Skipf("no home dir available: %s", err)
+	}
+
+	if got, want := Root(), filepath.Join(home, ".local", "share", "gofml"); got != want {
+		t.Errorf("Root() = %q, want %q", got, want)
+	}
+}
+
+func TestCacheDirUsesXDGCacheHome(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+
+	if got, want := CacheDir(), filepath.Join("/xdg/cache", "gofml"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+
+	if got, want := TemplateCacheDir(), filepath.Join("/xdg/cache", "gofml", "templates"); got != want {
+		t.Errorf("TemplateCacheDir() = %q, want %q", got, want)
+	}
+}
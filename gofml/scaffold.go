@@ -0,0 +1,307 @@
+package gofml
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/rubensayshi/gofml/gofml/paths"
+)
+
+// Scaffold generates the starter file tree for a project template.
+type Scaffold interface {
+	// Files returns the scaffold's file tree, rendered against task, keyed
+	// by path relative to task.ProjectPath.
+	Files(task *InitTask) (map[string][]byte, error)
+}
+
+// scaffolds is the registry of named, built-in scaffolds available via
+// `init -template`.
+var scaffolds = map[string]Scaffold{
+	"main": mainScaffold{},
+	"lib":  libScaffold{},
+	"cli":  cliScaffold{},
+	"grpc": grpcScaffold{},
+}
+
+// renderFiles parses each template in templates (keyed by output path) and
+// executes it against task, the way writeEnvrc renders the .envrc template.
+func renderFiles(task *InitTask, templates map[string]string) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(templates))
+
+	for name, tmplText := range templates {
+		tmpl, err := template.New(name).Parse(tmplText)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+
+		if err := tmpl.Execute(&buf, task); err != nil {
+			return nil, err
+		}
+
+		files[name] = buf.Bytes()
+	}
+
+	return files, nil
+}
+
+type mainScaffold struct{}
+
+var mainScaffoldFiles = map[string]string{
+	"main.go": `package main
+
+func main() {
+}
+`,
+}
+
+func (mainScaffold) Files(task *InitTask) (map[string][]byte, error) {
+	return renderFiles(task, mainScaffoldFiles)
+}
+
+type libScaffold struct{}
+
+var libScaffoldTemplate = `package {{.ProjectName}}
+`
+
+func (libScaffold) Files(task *InitTask) (map[string][]byte, error) {
+	return renderFiles(task, map[string]string{
+		task.ProjectName + ".go": libScaffoldTemplate,
+	})
+}
+
+// cliScaffold wires up a main.go + cmd/ package around github.com/spf13/cobra,
+// modeled after the Root command tree gofml itself uses.
+type cliScaffold struct{}
+
+var cliScaffoldFiles = map[string]string{
+	"main.go": `package main
+
+import "{{.ImportPath}}/cmd"
+
+func main() {
+	cmd.Execute()
+}
+`,
+	"cmd/root.go": `package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "{{.ProjectName}}",
+	Short: "{{.ProjectName}} command-line tool",
+}
+
+// Execute runs the root command.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`,
+	"cmd/version.go": `package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "print the {{.ProjectName}} version",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("{{.ProjectName}} version dev")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
+`,
+}
+
+func (cliScaffold) Files(task *InitTask) (map[string][]byte, error) {
+	return renderFiles(task, cliScaffoldFiles)
+}
+
+type grpcScaffold struct{}
+
+var grpcScaffoldFiles = map[string]string{
+	"proto/service.proto": `syntax = "proto3";
+
+package {{.ProjectName}};
+
+option go_package = "{{.ImportPath}}/proto";
+
+service Service {
+}
+`,
+	"internal/service/service.go": `package service
+
+// Service implements the {{.ProjectName}} gRPC service.
+type Service struct {
+}
+`,
+	"cmd/server/main.go": `package main
+
+import (
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"{{.ImportPath}}/internal/service"
+)
+
+func main() {
+	lis, err := net.Listen("tcp", ":50051")
+
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	srv := grpc.NewServer()
+	_ = service.Service{}
+
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
+`,
+}
+
+func (grpcScaffold) Files(task *InitTask) (map[string][]byte, error) {
+	return renderFiles(task, grpcScaffoldFiles)
+}
+
+// dirScaffold renders every file under a local directory (skipping .git
+// directories) as a text/template against the InitTask, for
+// `-template <path>` and cloned git templates.
+type dirScaffold struct {
+	dir string
+}
+
+func (s dirScaffold) Files(task *InitTask) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+
+		if err != nil {
+			return err
+		}
+
+		contents, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.New(rel).Parse(string(contents))
+
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+
+		if err := tmpl.Execute(&buf, task); err != nil {
+			return err
+		}
+
+		files[rel] = buf.Bytes()
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// resolveScaffold resolves name to a Scaffold: a built-in name, a local
+// directory, or a git URL that is cloned into the template cache.
+func resolveScaffold(name string) (Scaffold, error) {
+	if scaffold, ok := scaffolds[name]; ok {
+		return scaffold, nil
+	}
+
+	dir, err := templateDir(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dirScaffold{dir: dir}, nil
+}
+
+// templateDir returns the local directory backing a `-template` value. Any
+// value that is not an existing directory is treated as a git URL and cloned
+// into the cache dir, unless that cache dir already exists.
+func templateDir(name string) (string, error) {
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return name, nil
+	}
+
+	cacheDir := filepath.Join(templateCacheRoot(), templateCacheKey(name))
+
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		fmt.Printf("gofml: cloning template %s into %s\n", name, cacheDir)
+
+		if err := os.MkdirAll(filepath.Dir(cacheDir), os.ModeDir|0775); err != nil {
+			return "", err
+		}
+
+		if err := runCmd("", os.Environ(), "git", "clone", "--depth", "1", name, cacheDir); err != nil {
+			return "", fmt.Errorf("gofml: failed to clone template %s: %w", name, err)
+		}
+	}
+
+	return cacheDir, nil
+}
+
+// templateCacheRoot is where cloned git templates are cached.
+func templateCacheRoot() string {
+	return paths.TemplateCacheDir()
+}
+
+// templateCacheKey returns a cache-dir name unique to the full template URL,
+// so two URLs that merely share a basename (e.g. github.com/org-a/templates
+// and gitlab.com/org-b/templates) don't collide on the same cache directory.
+// The basename is kept as a prefix purely so the cache dir stays readable.
+func templateCacheKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	base := filepath.Base(strings.TrimSuffix(name, ".git"))
+
+	return base + "-" + hex.EncodeToString(sum[:])[:16]
+}
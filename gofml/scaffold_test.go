@@ -0,0 +1,63 @@
+package gofml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveScaffoldBuiltins(t *testing.T) {
+	for name := range scaffolds {
+		t.Run(name, func(t *testing.T) {
+			scaffold, err := resolveScaffold(name)
+
+			if err != nil {
+				t.Fatalf("resolveScaffold(%q) error = %v", name, err)
+			}
+
+			files, err := scaffold.Files(&InitTask{ImportPath: "github.com/example/foo", ProjectName: "foo"})
+
+			if err != nil {
+				t.Fatalf("Files() error = %v", err)
+			}
+
+			if len(files) == 0 {
+				t.Errorf("Files() returned no files")
+			}
+		})
+	}
+}
+
+func TestRenderFiles(t *testing.T) {
+	task := &InitTask{ImportPath: "github.com/example/foo", ProjectName: "foo"}
+
+	files, err := renderFiles(task, map[string]string{
+		"main.go": "package {{.ProjectName}}\n\n// {{.ImportPath}}\n",
+	})
+
+	if err != nil {
+		t.Fatalf("renderFiles() error = %v", err)
+	}
+
+	got := string(files["main.go"])
+
+	if !strings.Contains(got, "package foo") {
+		t.Errorf("rendered file = %q, want it to contain %q", got, "package foo")
+	}
+
+	if !strings.Contains(got, task.ImportPath) {
+		t.Errorf("rendered file = %q, want it to contain %q", got, task.ImportPath)
+	}
+}
+
+func TestTemplateCacheKeyDiffersAcrossHosts(t *testing.T) {
+	a := templateCacheKey("https://github.com/org-a/templates.git")
+	b := templateCacheKey("https://gitlab.com/org-b/templates.git")
+
+	if a == b {
+		t.Errorf("templateCacheKey collided for two different URLs: %q", a)
+	}
+
+	if !strings.HasPrefix(a, "templates-") || !strings.HasPrefix(b, "templates-") {
+		t.Errorf("templateCacheKey should keep the basename as a prefix, got %q and %q", a, b)
+	}
+}